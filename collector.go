@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+)
+
+// Collector is a dynamic source of metrics that doesn't fit the static
+// NewCounter/NewFloatCounter model, e.g. a scraper over a database or
+// cgroup stats.
+//
+// Register collectors via Set.RegisterCollector. They are consulted on
+// every WritePrometheus call.
+type Collector interface {
+	// Describe sends the descriptors of all metrics c can possibly report
+	// to descs. Set.RegisterCollector does not call Describe, so the
+	// descriptors are not checked against what Collect reports.
+	Describe(descs chan<- *Desc)
+
+	// Collect sends each of c's current metrics to metrics.
+	Collect(metrics chan<- Metric)
+}
+
+// Metric is a single metric value produced by a Collector.
+type Metric interface {
+	// Desc returns the descriptor for the Metric.
+	Desc() *Desc
+
+	// Write marshals the current value of the metric to w in Prometheus
+	// text exposition format, using Desc().String() as the metric name.
+	Write(w io.Writer) error
+}
+
+// DescribeByCollect is a helper for implementing Collector.Describe for
+// collectors whose metric set is static: it calls c.Collect once and
+// reroutes the resulting metrics' descriptors to descs.
+//
+// This mirrors client_golang's DescribeByCollect, easing ports of existing
+// Collector implementations.
+func DescribeByCollect(c Collector, descs chan<- *Desc) {
+	metrics := make(chan Metric)
+	go func() {
+		c.Collect(metrics)
+		close(metrics)
+	}()
+	for m := range metrics {
+		descs <- m.Desc()
+	}
+}
+
+// RegisterCollector registers c in the default set.
+func RegisterCollector(c Collector) {
+	defaultSet.RegisterCollector(c)
+}
+
+// RegisterCollector registers c in s. c.Collect is consulted on every
+// subsequent s.WritePrometheus call.
+func (s *Set) RegisterCollector(c Collector) {
+	s.mu.Lock()
+	s.collectors = append(s.collectors, c)
+	s.mu.Unlock()
+}
+
+// constMetric is a Metric with a fixed, already-computed value. It's used by
+// built-in collectors such as GoCollector and ProcessCollector, which sample
+// their values upfront rather than wrapping a Counter/Histogram.
+type constMetric struct {
+	name string
+	val  float64
+}
+
+func newConstMetric(name string, val float64) Metric {
+	return &constMetric{name: name, val: val}
+}
+
+func (m *constMetric) Desc() *Desc {
+	return NewDesc(m.name, "")
+}
+
+func (m *constMetric) Write(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s %g\n", m.name, m.val)
+	return err
+}
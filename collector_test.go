@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// fixedCollector is a minimal Collector reporting a single constant metric,
+// used to exercise RegisterCollector/DescribeByCollect/WritePrometheus.
+type fixedCollector struct {
+	name string
+	val  float64
+}
+
+func (fc *fixedCollector) Describe(descs chan<- *Desc) {
+	DescribeByCollect(fc, descs)
+}
+
+func (fc *fixedCollector) Collect(ch chan<- Metric) {
+	ch <- newConstMetric(fc.name, fc.val)
+}
+
+func TestRegisterCollectorWritePrometheus(t *testing.T) {
+	set := NewSet()
+	set.RegisterCollector(&fixedCollector{name: "widgets_total", val: 42})
+
+	var buf bytes.Buffer
+	set.WritePrometheus(&buf)
+
+	if got := buf.String(); got != "widgets_total 42\n" {
+		t.Errorf("WritePrometheus output = %q, want %q", got, "widgets_total 42\n")
+	}
+}
+
+func TestRegisterCollectorAlongsideRegularMetrics(t *testing.T) {
+	set := NewSet()
+	set.NewCounter("requests_total").Add(3)
+	set.RegisterCollector(&fixedCollector{name: "widgets_total", val: 42})
+
+	var buf bytes.Buffer
+	set.WritePrometheus(&buf)
+	out := buf.String()
+
+	if !strings.Contains(out, "requests_total 3\n") {
+		t.Errorf("output missing registered counter; got:\n%s", out)
+	}
+	if !strings.Contains(out, "widgets_total 42\n") {
+		t.Errorf("output missing collector metric; got:\n%s", out)
+	}
+}
+
+func TestDescribeByCollect(t *testing.T) {
+	fc := &fixedCollector{name: "widgets_total", val: 42}
+
+	descs := make(chan *Desc, 1)
+	fc.Describe(descs)
+	close(descs)
+
+	var got []string
+	for d := range descs {
+		got = append(got, d.String())
+	}
+	if len(got) != 1 || got[0] != "widgets_total" {
+		t.Errorf("Describe() = %v, want [widgets_total]", got)
+	}
+}
@@ -27,6 +27,8 @@ func NewCounter(name string) *Counter {
 type Counter struct {
 	n             uint64
 	lastWriteTime int64
+
+	exemplar atomic.Pointer[exemplar]
 }
 
 // Inc increments c.
@@ -52,6 +54,20 @@ func (c *Counter) AddInt64(n int64) {
 	atomic.StoreInt64(&c.lastWriteTime, time.Now().Unix())
 }
 
+// AddWithExemplar adds n to c and attaches an OpenMetrics exemplar to the
+// observation, associating it with traceID and the given labels.
+//
+// The exemplar is only emitted by Set.WriteOpenMetrics; WritePrometheus
+// ignores it. If the serialized labels exceed the OpenMetrics 128-byte
+// limit, the exemplar is dropped silently and the counter is still
+// incremented.
+func (c *Counter) AddWithExemplar(n int, labels map[string]string, traceID string) {
+	c.Add(n)
+	if e := newExemplar(float64(n), labels, traceID); e != nil {
+		c.exemplar.Store(e)
+	}
+}
+
 // Get returns the current value for c.
 func (c *Counter) Get() uint64 {
 	return atomic.LoadUint64(&c.n)
@@ -73,6 +89,17 @@ func (c *Counter) marshalTo(prefix string, w io.Writer) {
 	fmt.Fprintf(w, "%s %d\n", prefix, v)
 }
 
+// marshalOpenMetricsTo marshals c with the given prefix to w in OpenMetrics
+// format, including its exemplar (if any).
+func (c *Counter) marshalOpenMetricsTo(prefix string, w io.Writer) {
+	v := c.Get()
+	fmt.Fprintf(w, "%s %d", prefix, v)
+	if e := c.exemplar.Load(); e != nil {
+		e.marshalTo(w)
+	}
+	fmt.Fprintf(w, "\n")
+}
+
 func (c *Counter) metricType() string {
 	return "counter"
 }
@@ -0,0 +1,28 @@
+package metrics
+
+// Desc is a metric descriptor, identifying a metric exposed by a Collector.
+//
+// It intentionally carries less metadata than client_golang's Desc, since
+// this package doesn't support variable/const label sets - a Desc's fqName
+// is expected to already include any labels, e.g. `foo{bar="baz"}`.
+type Desc struct {
+	fqName string
+	help   string
+}
+
+// NewDesc returns a new Desc for a metric named fqName.
+//
+// help is a human-readable description of the metric. It currently isn't
+// exposed anywhere (this package doesn't emit HELP/TYPE comments), but is
+// accepted for API compatibility with client_golang-style collectors.
+func NewDesc(fqName, help string) *Desc {
+	return &Desc{
+		fqName: fqName,
+		help:   help,
+	}
+}
+
+// String returns the fully-qualified metric name for d.
+func (d *Desc) String() string {
+	return d.fqName
+}
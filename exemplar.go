@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxExemplarLabelsLen is the limit on the length of an exemplar's serialized
+// labels, including the wrapping braces: 128 bytes of UTF-8.
+const maxExemplarLabelsLen = 128
+
+// exemplar is a single OpenMetrics exemplar: a sample from a
+// higher-cardinality system (typically a trace) backing a particular
+// observation. See https://github.com/OpenObservability/OpenMetrics/blob/main/specification/OpenMetrics.md#exemplars
+type exemplar struct {
+	value     float64
+	labels    string
+	timestamp float64
+}
+
+// newExemplar returns a new exemplar for the given observed value, or nil if
+// there are no labels to serialize or the serialized labels exceed the
+// OpenMetrics size limit.
+func newExemplar(value float64, labels map[string]string, traceID string) *exemplar {
+	s := formatExemplarLabels(labels, traceID)
+	if s == "" || len(s) > maxExemplarLabelsLen {
+		return nil
+	}
+	return &exemplar{
+		value:     value,
+		labels:    s,
+		timestamp: float64(time.Now().UnixNano()) / 1e9,
+	}
+}
+
+func formatExemplarLabels(labels map[string]string, traceID string) string {
+	if len(labels) == 0 && traceID == "" {
+		return ""
+	}
+	parts := make([]string, 0, len(labels)+1)
+	if traceID != "" {
+		parts = append(parts, fmt.Sprintf(`trace_id="%s"`, traceID))
+	}
+	for k, v := range labels {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, k, v))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// marshalTo writes " # {labels} value timestamp" to w for e, matching the
+// OpenMetrics exemplar syntax.
+func (e *exemplar) marshalTo(w io.Writer) {
+	fmt.Fprintf(w, " # %s %g %.3f", e.labels, e.value, e.timestamp)
+}
+
+// exemplarMetric is implemented by metric types that can carry an
+// OpenMetrics exemplar (currently Counter and FloatCounter). Set.WriteOpenMetrics
+// uses it to render the exemplar inline with the metric's value.
+type exemplarMetric interface {
+	marshalOpenMetricsTo(prefix string, w io.Writer)
+}
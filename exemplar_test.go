@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNewExemplarEmptyReturnsNil(t *testing.T) {
+	if e := newExemplar(1, nil, ""); e != nil {
+		t.Fatalf("newExemplar with no labels and no trace ID = %+v, want nil", e)
+	}
+	if e := newExemplar(1, map[string]string{}, ""); e != nil {
+		t.Fatalf("newExemplar with empty labels map and no trace ID = %+v, want nil", e)
+	}
+}
+
+func TestNewExemplarDropsOversizedLabels(t *testing.T) {
+	longID := strings.Repeat("a", maxExemplarLabelsLen)
+	if e := newExemplar(1, nil, longID); e != nil {
+		t.Fatalf("newExemplar with oversized labels = %+v, want nil", e)
+	}
+}
+
+func TestNewExemplarKeepsWithinLimit(t *testing.T) {
+	e := newExemplar(1, map[string]string{"a": "b"}, "trace-1")
+	if e == nil {
+		t.Fatal("newExemplar with small labels returned nil")
+	}
+	if !strings.Contains(e.labels, `trace_id="trace-1"`) {
+		t.Errorf("labels = %q, want it to contain trace_id", e.labels)
+	}
+	if !strings.Contains(e.labels, `a="b"`) {
+		t.Errorf("labels = %q, want it to contain a=\"b\"", e.labels)
+	}
+}
+
+func TestSetWriteOpenMetricsIncludesExemplar(t *testing.T) {
+	set := NewSet()
+	c := set.NewCounter("requests_total")
+	c.AddWithExemplar(1, nil, "abc123")
+
+	var buf bytes.Buffer
+	set.WriteOpenMetrics(&buf)
+	out := buf.String()
+
+	if !strings.Contains(out, `requests_total 1 # {trace_id="abc123"}`) {
+		t.Errorf("output missing inline exemplar; got:\n%s", out)
+	}
+}
+
+func TestSetWriteOpenMetricsOmitsExemplarWhenEmpty(t *testing.T) {
+	set := NewSet()
+	c := set.NewCounter("requests_total")
+	c.AddWithExemplar(1, nil, "")
+
+	var buf bytes.Buffer
+	set.WriteOpenMetrics(&buf)
+	out := buf.String()
+
+	if strings.Contains(out, "#") {
+		t.Errorf("output should have no exemplar marker when labels and trace ID are empty; got:\n%s", out)
+	}
+	if !strings.Contains(out, "requests_total 1\n") {
+		t.Errorf("output missing plain counter line; got:\n%s", out)
+	}
+}
+
+func TestSetWritePrometheusIgnoresExemplar(t *testing.T) {
+	set := NewSet()
+	c := set.NewCounter("requests_total")
+	c.AddWithExemplar(1, nil, "abc123")
+
+	var buf bytes.Buffer
+	set.WritePrometheus(&buf)
+	out := buf.String()
+
+	if strings.Contains(out, "#") {
+		t.Errorf("WritePrometheus must not emit exemplars; got:\n%s", out)
+	}
+}
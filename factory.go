@@ -0,0 +1,47 @@
+package metrics
+
+// Factory is a promauto-style convenience wrapper around a Set: it creates
+// metrics and registers them via Set.MustRegister, panicking immediately on
+// a name/type conflict rather than letting it surface later as a confusing
+// type assertion failure.
+//
+// This is the main difference from GetOrCreateCounter and friends, which
+// treat re-registering the same name as the same type as a no-op.
+type Factory struct {
+	set *Set
+}
+
+// NewFactoryWith returns a Factory that registers metrics in set.
+func NewFactoryWith(set *Set) *Factory {
+	return &Factory{set: set}
+}
+
+// NewCounter creates, registers and returns a new Counter named name.
+func (f *Factory) NewCounter(name string) *Counter {
+	c := &Counter{}
+	f.set.MustRegister(name, c)
+	return c
+}
+
+// NewFloatCounter creates, registers and returns a new FloatCounter named name.
+func (f *Factory) NewFloatCounter(name string) *FloatCounter {
+	fc := &FloatCounter{}
+	f.set.MustRegister(name, fc)
+	return fc
+}
+
+// NewHistogram creates, registers and returns a new Histogram named name
+// with the given bucket boundaries.
+func (f *Factory) NewHistogram(name string, bucketBounds []float64) *Histogram {
+	h := newHistogram(bucketBounds)
+	f.set.MustRegister(name, h)
+	return h
+}
+
+// NewSummary creates, registers and returns a new Summary named name with
+// the given quantile objectives.
+func (f *Factory) NewSummary(name string, objectives map[float64]float64) *Summary {
+	sm := newSummary(objectives)
+	f.set.MustRegister(name, sm)
+	return sm
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import "testing"
+
+func TestFactoryRegistersMetrics(t *testing.T) {
+	set := NewSet()
+	f := NewFactoryWith(set)
+
+	c := f.NewCounter("requests_total")
+	c.Inc()
+	if got := c.Get(); got != 1 {
+		t.Errorf("Counter.Get() = %v, want 1", got)
+	}
+}
+
+func TestFactoryPanicsOnTypeConflict(t *testing.T) {
+	set := NewSet()
+	f := NewFactoryWith(set)
+	f.NewCounter("foo")
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("registering foo as a Histogram after registering it as a Counter should panic")
+		}
+	}()
+	f.NewHistogram("foo", nil)
+}
+
+func TestSetRegisterReturnsErrorOnConflict(t *testing.T) {
+	set := NewSet()
+	if err := set.Register("foo", &Counter{}); err != nil {
+		t.Fatalf("first Register: %v", err)
+	}
+	if err := set.Register("foo", &Counter{}); err == nil {
+		t.Fatal("expected error re-registering the same name, even with the same type")
+	}
+	if err := set.Register("foo", &Histogram{}); err == nil {
+		t.Fatal("expected error registering foo as a different type")
+	}
+}
@@ -29,6 +29,8 @@ type FloatCounter struct {
 	mu            sync.Mutex
 	n             float64
 	lastWriteTime int64
+
+	exemplar atomic.Pointer[exemplar]
 }
 
 // Add adds n to fc.
@@ -64,6 +66,19 @@ func (fc *FloatCounter) Set(n float64) {
 	fc.mu.Unlock()
 }
 
+// AddFloatWithExemplar adds n to fc and attaches an OpenMetrics exemplar to
+// the observation, associating it with traceID and the given labels.
+//
+// The exemplar is only emitted by Set.WriteOpenMetrics; WritePrometheus
+// ignores it. If the serialized labels exceed the OpenMetrics 128-byte
+// limit, the exemplar is dropped silently and fc is still incremented.
+func (fc *FloatCounter) AddFloatWithExemplar(n float64, labels map[string]string, traceID string) {
+	fc.Add(n)
+	if e := newExemplar(n, labels, traceID); e != nil {
+		fc.exemplar.Store(e)
+	}
+}
+
 // marshalTo marshals fc with the given prefix to w.
 func (fc *FloatCounter) marshalTo(prefix string, w io.Writer) {
 	v := fc.Get()
@@ -74,6 +89,17 @@ func (c *FloatCounter) getLastWriteTime() int64 {
 	return atomic.LoadInt64(&c.lastWriteTime)
 }
 
+// marshalOpenMetricsTo marshals fc with the given prefix to w in OpenMetrics
+// format, including its exemplar (if any).
+func (fc *FloatCounter) marshalOpenMetricsTo(prefix string, w io.Writer) {
+	v := fc.Get()
+	fmt.Fprintf(w, "%s %g", prefix, v)
+	if e := fc.exemplar.Load(); e != nil {
+		e.marshalTo(w)
+	}
+	fmt.Fprintf(w, "\n")
+}
+
 func (fc *FloatCounter) metricType() string {
 	return "counter"
 }
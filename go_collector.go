@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+)
+
+// NewGoCollector returns a Collector exposing Go runtime metrics sourced from
+// the runtime/metrics package.
+//
+// runtime/metrics.All() is consulted once at construction time to enumerate
+// the available samples, so newly-added runtime metrics on newer Go versions
+// show up automatically without code changes here. The sample slice is
+// cached and reused on every Collect call; Collect still allocates a metric
+// name and a Metric value for every sample on each call.
+func NewGoCollector() *GoCollector {
+	descs := metrics.All()
+	samples := make([]metrics.Sample, len(descs))
+	for i := range descs {
+		samples[i].Name = descs[i].Name
+	}
+	return &GoCollector{
+		descs:   descs,
+		samples: samples,
+	}
+}
+
+// GoCollector is a Collector exposing metrics from the Go runtime.
+type GoCollector struct {
+	descs   []metrics.Description
+	samples []metrics.Sample
+}
+
+// Describe implements Collector.
+func (gc *GoCollector) Describe(descs chan<- *Desc) {
+	DescribeByCollect(gc, descs)
+}
+
+// Collect implements Collector.
+func (gc *GoCollector) Collect(ch chan<- Metric) {
+	metrics.Read(gc.samples)
+	for i := range gc.samples {
+		s := &gc.samples[i]
+		switch s.Value.Kind() {
+		case metrics.KindUint64:
+			name := goMetricName(s.Name, gc.descs[i].Cumulative)
+			ch <- newConstMetric(name, float64(s.Value.Uint64()))
+		case metrics.KindFloat64:
+			name := goMetricName(s.Name, gc.descs[i].Cumulative)
+			ch <- newConstMetric(name, s.Value.Float64())
+		case metrics.KindFloat64Histogram:
+			// Histogram samples are always reported as Cumulative by
+			// runtime/metrics, but that describes the underlying counts
+			// (which only grow), not the sample itself - it's rendered as
+			// a Prometheus histogram (_bucket/_sum/_count), not a counter,
+			// so no "_total" suffix applies here.
+			name := goMetricName(s.Name, false)
+			ch <- newGoHistogramMetric(name, s.Value.Float64Histogram())
+		}
+	}
+}
+
+// goMetricName translates a runtime/metrics name such as
+// "/gc/heap/allocs:bytes" into a Prometheus-style name such as
+// "go_gc_heap_allocs_bytes_total".
+func goMetricName(rmName string, cumulative bool) string {
+	path, unit, _ := strings.Cut(strings.TrimPrefix(rmName, "/"), ":")
+	name := "go_" + strings.ReplaceAll(path, "/", "_")
+	if unit != "" {
+		name += "_" + unit
+	}
+	if cumulative {
+		name += "_total"
+	}
+	return name
+}
+
+// goHistogramMetric adapts a runtime/metrics Float64Histogram sample to the
+// Metric interface, emitting it as a native Prometheus histogram.
+type goHistogramMetric struct {
+	name string
+	h    *metrics.Float64Histogram
+}
+
+func newGoHistogramMetric(name string, h *metrics.Float64Histogram) Metric {
+	return &goHistogramMetric{name: name, h: h}
+}
+
+func (m *goHistogramMetric) Desc() *Desc {
+	return NewDesc(m.name, "")
+}
+
+func (m *goHistogramMetric) Write(w io.Writer) error {
+	var cumulative uint64
+	var weightedSum float64
+	for i, count := range m.h.Counts {
+		lo, hi := m.h.Buckets[i], m.h.Buckets[i+1]
+		if count != 0 {
+			// The runtime doesn't report a sum, so approximate it from the
+			// bucket midpoint. Good enough for dashboards, not for alerts
+			// that need an exact average. Open-ended buckets (e.g. the
+			// -Inf..x bucket that several runtime histograms, such as
+			// /gc/pauses:seconds, start with) have no midpoint, so fall
+			// back to the finite bound instead of letting Inf*0 or
+			// Inf-Inf poison the sum with NaN.
+			switch {
+			case math.IsInf(lo, -1) && math.IsInf(hi, 1):
+				// Unbounded on both sides: no finite bound to approximate with.
+			case math.IsInf(lo, -1):
+				weightedSum += hi * float64(count)
+			case math.IsInf(hi, 1):
+				weightedSum += lo * float64(count)
+			default:
+				weightedSum += (lo + hi) / 2 * float64(count)
+			}
+		}
+
+		cumulative += count
+		le := formatGoHistogramBound(hi)
+		if _, err := fmt.Fprintf(w, "%s %d\n", addTag(withSuffix(m.name, "_bucket"), "le", le), cumulative); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "%s %g\n", withSuffix(m.name, "_sum"), weightedSum); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s %d\n", withSuffix(m.name, "_count"), cumulative)
+	return err
+}
+
+func formatGoHistogramBound(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
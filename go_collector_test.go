@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGoMetricName(t *testing.T) {
+	for _, tc := range []struct {
+		rmName     string
+		cumulative bool
+		want       string
+	}{
+		{"/gc/heap/allocs:bytes", true, "go_gc_heap_allocs_bytes_total"},
+		{"/sched/latencies:seconds", false, "go_sched_latencies_seconds"},
+		{"/memory/classes/heap/free:bytes", false, "go_memory_classes_heap_free_bytes"},
+	} {
+		if got := goMetricName(tc.rmName, tc.cumulative); got != tc.want {
+			t.Errorf("goMetricName(%q, %v) = %q, want %q", tc.rmName, tc.cumulative, got, tc.want)
+		}
+	}
+}
+
+// TestGoCollectorHistogramNamesHaveNoTotalSuffix guards against a regression
+// where GoCollector.Collect applied a sample's Cumulative bit (which is true
+// for every runtime/metrics histogram, e.g. /gc/pauses:seconds) to the
+// histogram's name, producing a malformed "_total_bucket"/"_total_sum" name.
+func TestGoCollectorHistogramNamesHaveNoTotalSuffix(t *testing.T) {
+	gc := NewGoCollector()
+	ch := make(chan Metric, 1024)
+	go func() {
+		gc.Collect(ch)
+		close(ch)
+	}()
+
+	sawHistogram := false
+	for m := range ch {
+		if _, ok := m.(*goHistogramMetric); !ok {
+			continue
+		}
+		sawHistogram = true
+		name := m.Desc().String()
+		if strings.HasSuffix(name, "_total") {
+			t.Errorf("histogram metric name %q must not end with \"_total\"", name)
+		}
+	}
+	if !sawHistogram {
+		t.Fatal("expected at least one histogram sample from the Go runtime (e.g. /gc/pauses:seconds)")
+	}
+}
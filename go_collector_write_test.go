@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"runtime/metrics"
+	"strings"
+	"testing"
+)
+
+// TestGoHistogramMetricWriteNoNaN reproduces the shape of runtime histograms
+// such as /gc/pauses:seconds and /sched/latencies:seconds, whose first
+// bucket is -Inf..x with a zero count in steady state. The _sum must stay
+// finite rather than going NaN from a -Inf*0 term.
+func TestGoHistogramMetricWriteNoNaN(t *testing.T) {
+	h := &metrics.Float64Histogram{
+		Buckets: []float64{math.Inf(-1), 0, 1, math.Inf(1)},
+		Counts:  []uint64{0, 3, 2},
+	}
+	m := newGoHistogramMetric("go_gc_pauses_seconds", h)
+
+	var buf bytes.Buffer
+	if err := m.Write(&buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "NaN") {
+		t.Fatalf("output contains NaN:\n%s", out)
+	}
+}
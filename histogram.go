@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync/atomic"
+)
+
+// NewHistogram registers and returns new Histogram with the given name and
+// bucket boundaries.
+//
+// bucketBounds is the list of upper (inclusive) bucket boundaries. It is
+// sorted automatically if needed. A trailing "+Inf" bucket is added
+// implicitly, so there is no need to add math.Inf(1) to bucketBounds.
+//
+// name must be valid Prometheus-compatible metric with possible labels, see
+// NewCounter for details.
+//
+// The returned Histogram is safe to use from concurrent goroutines.
+func NewHistogram(name string, bucketBounds []float64) *Histogram {
+	return defaultSet.NewHistogram(name, bucketBounds)
+}
+
+// Histogram is a Prometheus-compatible histogram with static bucket boundaries.
+//
+// See https://prometheus.io/docs/concepts/metric_types/#histogram for details.
+type Histogram struct {
+	// bounds holds the sorted, upper (inclusive) bucket boundaries, excluding +Inf.
+	bounds []float64
+
+	// counts holds per-bucket observation counts, including the trailing +Inf bucket.
+	// Updated with plain atomics, so Observe stays lock-free.
+	counts []uint64
+
+	// sumBits is math.Float64bits of the running sum. There is no portable
+	// atomic float64 add, so updates go through a CAS loop.
+	sumBits uint64
+}
+
+func newHistogram(bucketBounds []float64) *Histogram {
+	bounds := append([]float64(nil), bucketBounds...)
+	sort.Float64s(bounds)
+	return &Histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+// Observe adds v to the observed values for h.
+func (h *Histogram) Observe(v float64) {
+	idx := sort.SearchFloat64s(h.bounds, v)
+	atomic.AddUint64(&h.counts[idx], 1)
+	for {
+		oldBits := atomic.LoadUint64(&h.sumBits)
+		newSum := math.Float64frombits(oldBits) + v
+		newBits := math.Float64bits(newSum)
+		if atomic.CompareAndSwapUint64(&h.sumBits, oldBits, newBits) {
+			break
+		}
+	}
+}
+
+// marshalTo marshals h with the given prefix to w.
+func (h *Histogram) marshalTo(prefix string, w io.Writer) {
+	var cumulative uint64
+	for i, bound := range h.bounds {
+		cumulative += atomic.LoadUint64(&h.counts[i])
+		le := strconv.FormatFloat(bound, 'g', -1, 64)
+		fmt.Fprintf(w, "%s %d\n", addTag(withSuffix(prefix, "_bucket"), "le", le), cumulative)
+	}
+	cumulative += atomic.LoadUint64(&h.counts[len(h.bounds)])
+	fmt.Fprintf(w, "%s %d\n", addTag(withSuffix(prefix, "_bucket"), "le", "+Inf"), cumulative)
+
+	sum := math.Float64frombits(atomic.LoadUint64(&h.sumBits))
+	fmt.Fprintf(w, "%s %g\n", withSuffix(prefix, "_sum"), sum)
+	fmt.Fprintf(w, "%s %d\n", withSuffix(prefix, "_count"), cumulative)
+}
+
+func (h *Histogram) metricType() string {
+	return "histogram"
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHistogramMarshalTo(t *testing.T) {
+	h := newHistogram([]float64{1, 2, 5})
+	for _, v := range []float64{0.5, 1, 1.5, 3, 10} {
+		h.Observe(v)
+	}
+
+	var buf bytes.Buffer
+	h.marshalTo("test_duration_seconds", &buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		`test_duration_seconds_bucket{le="1"} 2`,
+		`test_duration_seconds_bucket{le="2"} 3`,
+		`test_duration_seconds_bucket{le="5"} 4`,
+		`test_duration_seconds_bucket{le="+Inf"} 5`,
+		`test_duration_seconds_sum 16`,
+		`test_duration_seconds_count 5`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q; got:\n%s", want, out)
+		}
+	}
+}
+
+func TestHistogramUnsortedBounds(t *testing.T) {
+	h := newHistogram([]float64{5, 1, 2})
+	if got := h.bounds; got[0] != 1 || got[1] != 2 || got[2] != 5 {
+		t.Fatalf("bounds not sorted: %v", got)
+	}
+}
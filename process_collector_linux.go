@@ -0,0 +1,134 @@
+//go:build linux
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// NewProcessCollector returns a Collector exposing process_* metrics read
+// from /proc/self, matching the metric names from the Prometheus client
+// conventions (process_cpu_seconds_total, process_resident_memory_bytes,
+// process_open_fds, process_start_time_seconds).
+//
+// It is Linux-only; on other platforms it returns a Collector that reports
+// no metrics.
+func NewProcessCollector() *ProcessCollector {
+	return &ProcessCollector{}
+}
+
+// ProcessCollector is a Collector exposing process-level resource usage.
+type ProcessCollector struct {
+	// clkTckOnce and clkTck cache the system clock tick rate, since
+	// /proc/self/stat reports CPU time in ticks rather than seconds.
+	clkTckOnce sync.Once
+	clkTck     float64
+}
+
+// Describe implements Collector.
+func (pc *ProcessCollector) Describe(descs chan<- *Desc) {
+	DescribeByCollect(pc, descs)
+}
+
+// Collect implements Collector.
+func (pc *ProcessCollector) Collect(ch chan<- Metric) {
+	if cpu, start, ok := pc.readStat(); ok {
+		ch <- newConstMetric("process_cpu_seconds_total", cpu)
+		ch <- newConstMetric("process_start_time_seconds", start)
+	}
+	if rss, ok := pc.readRSS(); ok {
+		ch <- newConstMetric("process_resident_memory_bytes", rss)
+	}
+	if fds, ok := pc.readOpenFDs(); ok {
+		ch <- newConstMetric("process_open_fds", fds)
+	}
+}
+
+// readStat parses /proc/self/stat, returning cumulative CPU seconds
+// (utime+stime) and the process start time as a Unix timestamp.
+func (pc *ProcessCollector) readStat() (cpuSeconds, startTimeSeconds float64, ok bool) {
+	data, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return 0, 0, false
+	}
+	// Process name may contain spaces/parens, so split after the closing ')'.
+	i := strings.LastIndexByte(string(data), ')')
+	if i < 0 {
+		return 0, 0, false
+	}
+	fields := strings.Fields(string(data)[i+2:])
+	// Fields are 0-indexed here, but correspond to proc(5) fields 3 onward.
+	// field 14 (index 11) = utime, field 15 (index 12) = stime,
+	// field 22 (index 19) = starttime, in clock ticks since boot.
+	if len(fields) < 20 {
+		return 0, 0, false
+	}
+	utime, err1 := strconv.ParseFloat(fields[11], 64)
+	stime, err2 := strconv.ParseFloat(fields[12], 64)
+	startTicks, err3 := strconv.ParseFloat(fields[19], 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, 0, false
+	}
+
+	clkTck := pc.getClkTck()
+	bootTime, err := readBootTimeSeconds()
+	if err != nil {
+		return 0, 0, false
+	}
+	cpuSeconds = (utime + stime) / clkTck
+	startTimeSeconds = bootTime + startTicks/clkTck
+	return cpuSeconds, startTimeSeconds, true
+}
+
+func (pc *ProcessCollector) readRSS() (float64, bool) {
+	f, err := os.Open("/proc/self/statm")
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	var size, rss float64
+	if _, err := fmt.Fscan(f, &size, &rss); err != nil {
+		return 0, false
+	}
+	return rss * float64(os.Getpagesize()), true
+}
+
+func (pc *ProcessCollector) readOpenFDs() (float64, bool) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return 0, false
+	}
+	return float64(len(entries)), true
+}
+
+func (pc *ProcessCollector) getClkTck() float64 {
+	pc.clkTckOnce.Do(func() {
+		// USER_HZ is 100 on virtually all Linux systems; there is no
+		// portable way to read sysconf(_SC_CLK_TCK) without cgo.
+		pc.clkTck = 100
+	})
+	return pc.clkTck
+}
+
+func readBootTimeSeconds() (float64, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "btime ") {
+			return strconv.ParseFloat(strings.TrimSpace(line[len("btime "):]), 64)
+		}
+	}
+	return 0, sc.Err()
+}
@@ -0,0 +1,23 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"testing"
+)
+
+func TestReadRSSUsesActualPageSize(t *testing.T) {
+	pc := NewProcessCollector()
+	rss, ok := pc.readRSS()
+	if !ok {
+		t.Fatal("readRSS() returned ok=false; /proc/self/statm should be readable in tests")
+	}
+	if rss <= 0 {
+		t.Fatalf("readRSS() = %v, want > 0", rss)
+	}
+	pageSize := float64(os.Getpagesize())
+	if ratio := rss / pageSize; ratio != float64(int64(ratio)) {
+		t.Fatalf("readRSS() = %v is not a whole multiple of the page size %v", rss, pageSize)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !linux
+
+package metrics
+
+// NewProcessCollector returns a Collector exposing process_* metrics.
+//
+// Process-level metrics are only available on Linux (they're read from
+// /proc/self); on other platforms the returned collector reports nothing.
+func NewProcessCollector() *ProcessCollector {
+	return &ProcessCollector{}
+}
+
+// ProcessCollector is a Collector exposing process-level resource usage.
+type ProcessCollector struct{}
+
+// Describe implements Collector.
+func (pc *ProcessCollector) Describe(descs chan<- *Desc) {
+	DescribeByCollect(pc, descs)
+}
+
+// Collect implements Collector.
+func (pc *ProcessCollector) Collect(ch chan<- Metric) {
+}
@@ -0,0 +1,229 @@
+// Package push provides a client for pushing metrics to a Prometheus
+// Pushgateway, for batch jobs that can't be scraped directly.
+//
+// Usage:
+//
+//	err := push.NewPusher("http://pushgateway:9091", "my_job").
+//		Grouping("instance", "foo").
+//		Collector(metrics.GetDefaultSet()).
+//		Push()
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nicolasgere/metrics"
+)
+
+// Format is the Prometheus exposition format used for the pushed payload.
+type Format string
+
+// FormatText is the classic Prometheus text exposition format.
+const FormatText Format = "text/plain; version=0.0.4; charset=utf-8"
+
+// FormatOpenMetrics is the OpenMetrics exposition format, which also carries
+// any exemplars attached via Counter.AddWithExemplar and
+// FloatCounter.AddFloatWithExemplar. Set it with Format to push
+// Set.WriteOpenMetrics output instead of Set.WritePrometheus output.
+const FormatOpenMetrics Format = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+type label struct {
+	name  string
+	value string
+}
+
+// Pusher pushes metrics to a Pushgateway, identified by a job name and an
+// optional set of grouping key/value pairs.
+type Pusher struct {
+	url string
+	job string
+
+	groupings []label
+
+	set *metrics.Set
+
+	client *http.Client
+	header http.Header
+	format Format
+
+	useBasicAuth bool
+	username     string
+	password     string
+
+	err error
+}
+
+// NewPusher returns a new Pusher pushing to the Pushgateway at url under the
+// given job name.
+func NewPusher(url, job string) *Pusher {
+	return &Pusher{
+		url:    strings.TrimSuffix(url, "/"),
+		job:    job,
+		client: &http.Client{},
+		header: make(http.Header),
+		format: FormatText,
+	}
+}
+
+// Grouping adds a grouping key/value pair identifying the pushed metrics,
+// e.g. Grouping("instance", "foo"). It may be called multiple times to add
+// more than one grouping label.
+func (p *Pusher) Grouping(name, value string) *Pusher {
+	if strings.Contains(name, "/") {
+		p.err = fmt.Errorf("grouping label name %q must not contain '/'", name)
+		return p
+	}
+	p.groupings = append(p.groupings, label{name: name, value: value})
+	return p
+}
+
+// Collector attaches set as the source of metrics to push.
+func (p *Pusher) Collector(set *metrics.Set) *Pusher {
+	p.set = set
+	return p
+}
+
+// BasicAuth configures HTTP basic auth credentials used for the push request.
+func (p *Pusher) BasicAuth(username, password string) *Pusher {
+	p.useBasicAuth = true
+	p.username = username
+	p.password = password
+	return p
+}
+
+// Client sets the *http.Client used to perform push requests.
+func (p *Pusher) Client(c *http.Client) *Pusher {
+	p.client = c
+	return p
+}
+
+// Header adds h to the headers sent with every push request.
+func (p *Pusher) Header(h http.Header) *Pusher {
+	for k, vs := range h {
+		for _, v := range vs {
+			p.header.Add(k, v)
+		}
+	}
+	return p
+}
+
+// Format sets the exposition format used for the pushed payload.
+func (p *Pusher) Format(f Format) *Pusher {
+	p.format = f
+	return p
+}
+
+// Push pushes the metrics, replacing any previously pushed metrics with the
+// same job and grouping (HTTP PUT).
+func (p *Pusher) Push() error {
+	return p.push(http.MethodPut)
+}
+
+// Add pushes the metrics, merging them into any previously pushed metrics
+// with the same job and grouping (HTTP POST).
+func (p *Pusher) Add() error {
+	return p.push(http.MethodPost)
+}
+
+// Delete deletes any previously pushed metrics with the same job and
+// grouping (HTTP DELETE).
+func (p *Pusher) Delete() error {
+	return p.doRequest(http.MethodDelete, nil)
+}
+
+func (p *Pusher) push(method string) error {
+	if p.set == nil {
+		return fmt.Errorf("push: no Set attached, call Collector first")
+	}
+	var buf bytes.Buffer
+	if p.format == FormatOpenMetrics {
+		p.set.WriteOpenMetrics(&buf)
+	} else {
+		p.set.WritePrometheus(&buf)
+	}
+	return p.doRequest(method, &buf)
+}
+
+func (p *Pusher) doRequest(method string, body io.Reader) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	u, err := p.fullURL()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return err
+	}
+	for k, vs := range p.header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", string(p.format))
+	}
+	if p.useBasicAuth {
+		req.SetBasicAuth(p.username, p.password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("push: error pushing to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusAccepted {
+		return nil
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("push: unexpected status code %d while pushing to %s: %s", resp.StatusCode, u, respBody)
+}
+
+// fullURL builds the Pushgateway URL for p, including the /metrics/job/...
+// path segments for the job name and groupings.
+func (p *Pusher) fullURL() (string, error) {
+	components := []string{"metrics", "job", escapeComponent(p.job)}
+	for _, g := range p.groupings {
+		components = append(components, escapeComponent(g.name), escapeComponent(g.value))
+	}
+	return p.url + "/" + strings.Join(components, "/"), nil
+}
+
+// escapeComponent URL-escapes a single grouping label name or value for use
+// as a Pushgateway URL path segment. Values containing '/' (or empty
+// values) can't be represented as a plain path segment, so they are
+// base64-encoded per the Pushgateway's "@base64" convention instead.
+func escapeComponent(s string) string {
+	if s == "" || strings.Contains(s, "/") {
+		return base64.RawURLEncoding.EncodeToString([]byte(s)) + "@base64"
+	}
+	return url.PathEscape(s)
+}
+
+// PushPeriodically calls Push every interval until ctx is done. It is meant
+// to be run in its own goroutine by long-running jobs that want to keep
+// their groupings fresh on the Pushgateway between scrapes.
+func PushPeriodically(ctx context.Context, p *Pusher, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = p.Push()
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package push
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nicolasgere/metrics"
+)
+
+func TestEscapeComponent(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+	}{
+		{"instance", "instance"},
+		{"foo bar", "foo%20bar"},
+		{"", "@base64"},
+		{"a/b", "YS9i@base64"},
+	} {
+		if got := escapeComponent(tc.in); got != tc.want {
+			t.Errorf("escapeComponent(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestFullURL(t *testing.T) {
+	p := NewPusher("http://pushgateway:9091", "my_job").Grouping("instance", "foo")
+	got, err := p.fullURL()
+	if err != nil {
+		t.Fatalf("fullURL: %v", err)
+	}
+	want := "http://pushgateway:9091/metrics/job/my_job/instance/foo"
+	if got != want {
+		t.Errorf("fullURL() = %q, want %q", got, want)
+	}
+}
+
+func TestFullURLTrimsTrailingSlash(t *testing.T) {
+	p := NewPusher("http://pushgateway:9091/", "my_job")
+	got, err := p.fullURL()
+	if err != nil {
+		t.Fatalf("fullURL: %v", err)
+	}
+	want := "http://pushgateway:9091/metrics/job/my_job"
+	if got != want {
+		t.Errorf("fullURL() = %q, want %q", got, want)
+	}
+}
+
+func TestPushSendsConfiguredFormatAndAuth(t *testing.T) {
+	var gotContentType, gotUser, gotPass string
+	var gotAuthOK bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		gotUser, gotPass, gotAuthOK = r.BasicAuth()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	set := metrics.NewSet()
+	set.NewCounter("requests_total").Add(1)
+
+	err := NewPusher(srv.URL, "my_job").
+		Collector(set).
+		Format(FormatOpenMetrics).
+		BasicAuth("alice", "secret").
+		Push()
+	if err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if gotContentType != string(FormatOpenMetrics) {
+		t.Errorf("Content-Type = %q, want %q", gotContentType, FormatOpenMetrics)
+	}
+	if !gotAuthOK || gotUser != "alice" || gotPass != "secret" {
+		t.Errorf("BasicAuth = (%q, %q, %v), want (alice, secret, true)", gotUser, gotPass, gotAuthOK)
+	}
+}
+
+func TestPushDefaultFormatIsText(t *testing.T) {
+	var gotContentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	set := metrics.NewSet()
+	set.NewCounter("requests_total").Add(1)
+
+	if err := NewPusher(srv.URL, "my_job").Collector(set).Push(); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if gotContentType != string(FormatText) {
+		t.Errorf("Content-Type = %q, want %q", gotContentType, FormatText)
+	}
+}
+
+func TestPushWithoutCollectorFails(t *testing.T) {
+	if err := NewPusher("http://example.invalid", "my_job").Push(); err == nil {
+		t.Fatal("Push without a Collector should return an error")
+	}
+}
+
+func TestPushSurfacesErrorStatusCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("boom"))
+	}))
+	defer srv.Close()
+
+	set := metrics.NewSet()
+	set.NewCounter("requests_total").Add(1)
+
+	err := NewPusher(srv.URL, "my_job").Collector(set).Push()
+	if err == nil {
+		t.Fatal("expected an error for a non-2xx response")
+	}
+}
+
+func TestPushAcceptsStatusAccepted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	set := metrics.NewSet()
+	set.NewCounter("requests_total").Add(1)
+
+	if err := NewPusher(srv.URL, "my_job").Collector(set).Push(); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+}
+
+func TestGroupingRejectsSlashInName(t *testing.T) {
+	set := metrics.NewSet()
+	p := NewPusher("http://example.invalid", "my_job").Collector(set).Grouping("bad/name", "v")
+	if err := p.Push(); err == nil {
+		t.Fatal("Push should surface the Grouping error for a '/' in the label name")
+	}
+}
@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+)
+
+// quantileStreamBatch is the number of raw observations quantileStream
+// buffers before folding them into the compressed sample list. Batching
+// amortizes the cost of the O(n) insertion/compression pass below across
+// several Insert calls instead of paying it on every single one.
+const quantileStreamBatch = 128
+
+// qSample is a single compressed sample tracked by quantileStream: a value
+// together with the rank-uncertainty bounds (width, delta) the CKMS
+// algorithm needs to bound its error.
+type qSample struct {
+	value float64
+	width float64
+	delta float64
+}
+
+// quantileStream is a biased quantile estimator implementing the streaming
+// algorithm from Cormode, Korn, Muthukrishnan and Srivastava, "Effective
+// Computation of Biased Quantiles over Data Streams" (ICDE 2005) - the same
+// algorithm client_golang's Summary uses via github.com/beorn7/perks/quantile,
+// reimplemented here to avoid an external dependency.
+//
+// Unlike sorting and indexing every raw observation, quantileStream keeps a
+// compressed sample list whose size is bounded by the configured target
+// quantiles/epsilons rather than by the number of observations seen, so
+// memory and per-Query cost stay small even under sustained high-volume
+// Observe traffic.
+type quantileStream struct {
+	// targets maps each target quantile to its allowed error (epsilon), as
+	// passed to NewSummary. It is read-only after construction, shared by
+	// every quantileStream in a Summary's set of time buckets.
+	targets map[float64]float64
+
+	n   float64   // total number of observations merged into l so far.
+	l   []qSample // compressed samples, kept sorted by value.
+	buf []float64 // unsorted observations awaiting the next flush.
+}
+
+func newQuantileStream(targets map[float64]float64) *quantileStream {
+	return &quantileStream{
+		targets: targets,
+		buf:     make([]float64, 0, quantileStreamBatch),
+	}
+}
+
+// Insert adds v to the stream.
+func (s *quantileStream) Insert(v float64) {
+	s.buf = append(s.buf, v)
+	if len(s.buf) == cap(s.buf) {
+		s.flush()
+	}
+}
+
+// Query returns the approximate q-quantile (0 <= q <= 1) of the values
+// inserted into s so far.
+func (s *quantileStream) Query(q float64) float64 {
+	s.flush()
+	if len(s.l) == 0 {
+		return 0
+	}
+	t := math.Ceil(q*s.n) + s.invariant(q*s.n)/2
+	p := s.l[0]
+	var r float64
+	for _, c := range s.l[1:] {
+		r += p.width
+		if r+c.width+c.delta > t {
+			return p.value
+		}
+		p = c
+	}
+	return p.value
+}
+
+// Samples returns the current compressed sample list, flushing any buffered
+// observations first. The returned slice aliases s's internal state and must
+// not be retained past the next call that mutates s.
+func (s *quantileStream) Samples() []qSample {
+	s.flush()
+	return s.l
+}
+
+// Merge folds samples - typically another quantileStream's Samples() - into s.
+func (s *quantileStream) Merge(samples []qSample) {
+	s.flush()
+	s.mergeSamples(samples)
+}
+
+// Reset discards every observation merged into s so far.
+func (s *quantileStream) Reset() {
+	s.n = 0
+	s.l = s.l[:0]
+	s.buf = s.buf[:0]
+}
+
+// flush folds any buffered raw observations into the compressed sample list.
+func (s *quantileStream) flush() {
+	if len(s.buf) == 0 {
+		return
+	}
+	sort.Float64s(s.buf)
+	samples := make([]qSample, len(s.buf))
+	for i, v := range s.buf {
+		samples[i] = qSample{value: v, width: 1}
+	}
+	s.mergeSamples(samples)
+	s.buf = s.buf[:0]
+}
+
+// mergeSamples inserts samples (which must be sorted by value) into s.l,
+// maintaining the CKMS invariant that every sample's width+delta stays
+// within s.invariant of its rank, then compresses adjacent samples that can
+// be combined without violating it.
+func (s *quantileStream) mergeSamples(samples []qSample) {
+	var r float64
+	i := 0
+	for _, sample := range samples {
+		inserted := false
+		for ; i < len(s.l); i++ {
+			if s.l[i].value > sample.value {
+				delta := math.Max(sample.delta, math.Floor(s.invariant(r))-1)
+				s.l = append(s.l, qSample{})
+				copy(s.l[i+1:], s.l[i:])
+				s.l[i] = qSample{value: sample.value, width: sample.width, delta: delta}
+				i++
+				inserted = true
+				break
+			}
+			r += s.l[i].width
+		}
+		if !inserted {
+			s.l = append(s.l, qSample{value: sample.value, width: sample.width})
+			i++
+		}
+		s.n += sample.width
+		r += sample.width
+	}
+	s.compress()
+}
+
+// compress merges adjacent samples whose combined width+delta still
+// satisfies the invariant at their rank, keeping the sample list close to
+// its minimal size for the configured epsilons.
+func (s *quantileStream) compress() {
+	if len(s.l) < 2 {
+		return
+	}
+	x := s.l[len(s.l)-1]
+	xi := len(s.l) - 1
+	r := s.n - 1 - x.width
+	for i := len(s.l) - 2; i >= 0; i-- {
+		c := s.l[i]
+		if c.width+x.width+x.delta <= s.invariant(r) {
+			x.width += c.width
+			s.l[xi] = x
+			s.l = append(s.l[:i], s.l[i+1:]...)
+			xi--
+		} else {
+			x = c
+			xi = i
+		}
+		r -= c.width
+	}
+}
+
+// invariant returns the maximum combined width+delta a sample at rank r may
+// have without exceeding any configured target's error bound - the minimum
+// of each target's bias function, as in Cormode et al.
+func (s *quantileStream) invariant(r float64) float64 {
+	min := math.MaxFloat64
+	for q, epsilon := range s.targets {
+		var f float64
+		if q*s.n <= r {
+			f = 2 * epsilon * r / q
+		} else {
+			f = 2 * epsilon * (s.n - r) / (1 - q)
+		}
+		if f < min {
+			min = f
+		}
+	}
+	if min == math.MaxFloat64 {
+		return 0
+	}
+	return min
+}
@@ -0,0 +1,297 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// metric is the interface implemented by every metric type registered in a Set.
+type metric interface {
+	// marshalTo marshals the metric with the given prefix (the full metric
+	// name, including labels, e.g. `foo{bar="baz"}`) to w.
+	marshalTo(prefix string, w io.Writer)
+
+	// metricType returns the Prometheus metric type, e.g. "counter" or "gauge".
+	metricType() string
+}
+
+// namedMetric is a metric together with its fully-qualified name.
+type namedMetric struct {
+	name   string
+	metric metric
+}
+
+// Set is a set of metrics.
+//
+// Metrics belonging to a Set are exported separately from metrics registered
+// in the default, global set. This is useful for tests and for exposing
+// metrics scoped to a single component.
+//
+// Set.WritePrometheus must be called for exporting metrics from the set.
+type Set struct {
+	mu         sync.Mutex
+	a          []*namedMetric
+	m          map[string]*namedMetric
+	collectors []Collector
+}
+
+// NewSet creates and returns new set of metrics.
+func NewSet() *Set {
+	return &Set{
+		m: make(map[string]*namedMetric),
+	}
+}
+
+// defaultSet is the default set of metrics, used by package-level functions
+// such as NewCounter and WritePrometheus.
+var defaultSet = NewSet()
+
+// GetDefaultSet returns the default set of metrics.
+func GetDefaultSet() *Set {
+	return defaultSet
+}
+
+// WritePrometheus writes all the metrics from the default set in Prometheus text
+// format to w.
+func WritePrometheus(w io.Writer) {
+	defaultSet.WritePrometheus(w)
+}
+
+// WritePrometheus writes all the metrics from s in Prometheus text format to w.
+func (s *Set) WritePrometheus(w io.Writer) {
+	s.mu.Lock()
+	nms := append([]*namedMetric(nil), s.a...)
+	collectors := append([]Collector(nil), s.collectors...)
+	s.mu.Unlock()
+
+	sort.Slice(nms, func(i, j int) bool {
+		return nms[i].name < nms[j].name
+	})
+	for _, nm := range nms {
+		nm.metric.marshalTo(nm.name, w)
+	}
+
+	for _, c := range collectors {
+		metrics := make(chan Metric)
+		go func(c Collector) {
+			c.Collect(metrics)
+			close(metrics)
+		}(c)
+		for m := range metrics {
+			m.Write(w)
+		}
+	}
+}
+
+// WriteOpenMetrics writes all the metrics from the default set to w, using
+// the OpenMetrics exposition format.
+func WriteOpenMetrics(w io.Writer) {
+	defaultSet.WriteOpenMetrics(w)
+}
+
+// WriteOpenMetrics writes all the metrics from s to w, using the
+// OpenMetrics exposition format.
+//
+// Unlike WritePrometheus, metrics carrying an exemplar (see
+// Counter.AddWithExemplar and FloatCounter.AddFloatWithExemplar) include it
+// inline, for scrapers that advertise application/openmetrics-text.
+func (s *Set) WriteOpenMetrics(w io.Writer) {
+	s.mu.Lock()
+	nms := append([]*namedMetric(nil), s.a...)
+	collectors := append([]Collector(nil), s.collectors...)
+	s.mu.Unlock()
+
+	sort.Slice(nms, func(i, j int) bool {
+		return nms[i].name < nms[j].name
+	})
+	for _, nm := range nms {
+		if em, ok := nm.metric.(exemplarMetric); ok {
+			em.marshalOpenMetricsTo(nm.name, w)
+			continue
+		}
+		nm.metric.marshalTo(nm.name, w)
+	}
+
+	for _, c := range collectors {
+		metrics := make(chan Metric)
+		go func(c Collector) {
+			c.Collect(metrics)
+			close(metrics)
+		}(c)
+		for m := range metrics {
+			m.Write(w)
+		}
+	}
+}
+
+// NewCounter registers and returns new counter with the given name in s.
+//
+// name must be valid Prometheus-compatible metric with possible labels, see
+// NewCounter (package-level) for details.
+//
+// The returned counter is safe to use from concurrent goroutines.
+func (s *Set) NewCounter(name string) *Counter {
+	c := &Counter{}
+	s.MustRegister(name, c)
+	return c
+}
+
+// GetOrCreateCounter returns registered counter in s with the given name
+// or creates new counter if s doesn't contain counter with the given name.
+func (s *Set) GetOrCreateCounter(name string) *Counter {
+	s.mu.Lock()
+	nm := s.m[name]
+	s.mu.Unlock()
+	if nm != nil {
+		c, ok := nm.metric.(*Counter)
+		if !ok {
+			panic(fmt.Errorf("BUG: metric %q isn't a Counter", name))
+		}
+		return c
+	}
+	return s.NewCounter(name)
+}
+
+// NewFloatCounter registers and returns new FloatCounter with the given name in s.
+func (s *Set) NewFloatCounter(name string) *FloatCounter {
+	fc := &FloatCounter{}
+	s.MustRegister(name, fc)
+	return fc
+}
+
+// GetOrCreateFloatCounter returns registered FloatCounter in s with the given name
+// or creates new FloatCounter if s doesn't contain FloatCounter with the given name.
+func (s *Set) GetOrCreateFloatCounter(name string) *FloatCounter {
+	s.mu.Lock()
+	nm := s.m[name]
+	s.mu.Unlock()
+	if nm != nil {
+		fc, ok := nm.metric.(*FloatCounter)
+		if !ok {
+			panic(fmt.Errorf("BUG: metric %q isn't a FloatCounter", name))
+		}
+		return fc
+	}
+	return s.NewFloatCounter(name)
+}
+
+// NewHistogram registers and returns new Histogram with the given name and
+// bucket boundaries in s.
+func (s *Set) NewHistogram(name string, bucketBounds []float64) *Histogram {
+	h := newHistogram(bucketBounds)
+	s.MustRegister(name, h)
+	return h
+}
+
+// GetOrCreateHistogram returns registered Histogram in s with the given name
+// or creates new Histogram with the given bucket boundaries if s doesn't
+// contain a Histogram with the given name.
+func (s *Set) GetOrCreateHistogram(name string, bucketBounds []float64) *Histogram {
+	s.mu.Lock()
+	nm := s.m[name]
+	s.mu.Unlock()
+	if nm != nil {
+		h, ok := nm.metric.(*Histogram)
+		if !ok {
+			panic(fmt.Errorf("BUG: metric %q isn't a Histogram", name))
+		}
+		return h
+	}
+	return s.NewHistogram(name, bucketBounds)
+}
+
+// NewSummary registers and returns new Summary with the given name and
+// quantile objectives in s.
+func (s *Set) NewSummary(name string, objectives map[float64]float64) *Summary {
+	sm := newSummary(objectives)
+	s.MustRegister(name, sm)
+	return sm
+}
+
+// GetOrCreateSummary returns registered Summary in s with the given name
+// or creates new Summary with the given objectives if s doesn't contain
+// a Summary with the given name.
+func (s *Set) GetOrCreateSummary(name string, objectives map[float64]float64) *Summary {
+	s.mu.Lock()
+	nm := s.m[name]
+	s.mu.Unlock()
+	if nm != nil {
+		sm, ok := nm.metric.(*Summary)
+		if !ok {
+			panic(fmt.Errorf("BUG: metric %q isn't a Summary", name))
+		}
+		return sm
+	}
+	return s.NewSummary(name, objectives)
+}
+
+// Register registers m under name in s, returning an error if name is
+// already registered - including, unlike GetOrCreateCounter and friends, the
+// case where it is already registered with the same metric type.
+//
+// This gives callers (such as Factory) the strict conflict detection users
+// expect from client_golang's promauto: registering "foo" as a Counter and
+// later as a Histogram fails loudly at registration time instead of
+// silently returning the original Counter.
+func (s *Set) Register(name string, m metric) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if nm, ok := s.m[name]; ok {
+		if nm.metric.metricType() != m.metricType() {
+			return fmt.Errorf("metrics: cannot register %q as %s: it is already registered as %s", name, m.metricType(), nm.metric.metricType())
+		}
+		return fmt.Errorf("metrics: %q is already registered", name)
+	}
+	nm := &namedMetric{
+		name:   name,
+		metric: m,
+	}
+	s.m[name] = nm
+	s.a = append(s.a, nm)
+	return nil
+}
+
+// MustRegister is like Register, but panics instead of returning an error.
+func (s *Set) MustRegister(name string, m metric) {
+	if err := s.Register(name, m); err != nil {
+		panic(err)
+	}
+}
+
+// splitMetricName splits name such as `foo{bar="baz"}` into the metric name
+// ("foo") and the comma-separated label body without braces (`bar="baz"`).
+// labels is "" if name has no labels.
+func splitMetricName(name string) (metricName, labels string) {
+	n := strings.IndexByte(name, '{')
+	if n < 0 {
+		return name, ""
+	}
+	metricName = name[:n]
+	labels = strings.TrimSuffix(name[n+1:], "}")
+	return metricName, labels
+}
+
+// withSuffix returns name with suffix appended to its metric name, preserving
+// any existing labels, e.g. withSuffix(`foo{bar="baz"}`, "_sum") returns
+// `foo_sum{bar="baz"}`.
+func withSuffix(name, suffix string) string {
+	metricName, labels := splitMetricName(name)
+	if labels == "" {
+		return metricName + suffix
+	}
+	return fmt.Sprintf("%s%s{%s}", metricName, suffix, labels)
+}
+
+// addTag returns name with an additional tag=`"value"` label placed before
+// any existing labels, e.g. addTag(`foo{bar="baz"}`, "le", "0.1") returns
+// `foo{le="0.1",bar="baz"}`.
+func addTag(name, tag, value string) string {
+	metricName, labels := splitMetricName(name)
+	if labels == "" {
+		return fmt.Sprintf(`%s{%s="%s"}`, metricName, tag, value)
+	}
+	return fmt.Sprintf(`%s{%s="%s",%s}`, metricName, tag, value, labels)
+}
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSummaryMaxAge     = 10 * time.Minute
+	defaultSummaryAgeBuckets = 5
+)
+
+// NewSummary registers and returns new Summary with the given name and
+// quantile objectives.
+//
+// objectives maps quantiles (e.g. 0.5, 0.9, 0.99) to their allowed error,
+// matching the client_golang SummaryOpts.Objectives convention. The error
+// bounds the approximation used by the underlying quantileStream sketch.
+//
+// name must be valid Prometheus-compatible metric with possible labels, see
+// NewCounter for details.
+//
+// The returned Summary is safe to use from concurrent goroutines.
+func NewSummary(name string, objectives map[float64]float64) *Summary {
+	return defaultSet.NewSummary(name, objectives)
+}
+
+// Summary is a Prometheus-compatible summary exposing streaming quantiles
+// over a sliding time window.
+//
+// Quantiles are estimated with a CKMS biased quantile sketch (see
+// quantileStream) rather than by storing every raw observation, so memory
+// and per-scrape CPU stay bounded regardless of observation volume.
+//
+// See https://prometheus.io/docs/concepts/metric_types/#summary for details.
+type Summary struct {
+	mu sync.Mutex
+
+	quantiles []float64
+	targets   map[float64]float64 // quantile -> allowed error, for quantileStream
+
+	maxAge     time.Duration
+	ageBuckets int
+	bucketAge  time.Duration
+
+	curBucket  int
+	buckets    []*quantileStream
+	lastRotate time.Time
+
+	sum   float64
+	count uint64
+}
+
+func newSummary(objectives map[float64]float64) *Summary {
+	quantiles := make([]float64, 0, len(objectives))
+	targets := make(map[float64]float64, len(objectives))
+	for q, epsilon := range objectives {
+		quantiles = append(quantiles, q)
+		targets[q] = epsilon
+	}
+	sort.Float64s(quantiles)
+
+	sm := &Summary{
+		quantiles:  quantiles,
+		targets:    targets,
+		maxAge:     defaultSummaryMaxAge,
+		ageBuckets: defaultSummaryAgeBuckets,
+		bucketAge:  defaultSummaryMaxAge / time.Duration(defaultSummaryAgeBuckets),
+		lastRotate: time.Now(),
+	}
+	sm.buckets = newQuantileStreamBuckets(sm.ageBuckets, targets)
+	return sm
+}
+
+func newQuantileStreamBuckets(n int, targets map[float64]float64) []*quantileStream {
+	buckets := make([]*quantileStream, n)
+	for i := range buckets {
+		buckets[i] = newQuantileStream(targets)
+	}
+	return buckets
+}
+
+// SetWindow configures the sliding time window used for quantile
+// calculation: samples older than maxAge are discarded, and the window is
+// tracked in ageBuckets increments so old samples age out gradually rather
+// than all at once.
+//
+// It must be called right after creating sm, before any Observe call.
+func (sm *Summary) SetWindow(maxAge time.Duration, ageBuckets int) *Summary {
+	if maxAge <= 0 {
+		panic(fmt.Errorf("metrics: maxAge must be positive, got %s", maxAge))
+	}
+	if ageBuckets <= 0 {
+		panic(fmt.Errorf("metrics: ageBuckets must be positive, got %d", ageBuckets))
+	}
+	sm.mu.Lock()
+	sm.maxAge = maxAge
+	sm.ageBuckets = ageBuckets
+	sm.bucketAge = maxAge / time.Duration(ageBuckets)
+	sm.buckets = newQuantileStreamBuckets(ageBuckets, sm.targets)
+	sm.curBucket = 0
+	sm.mu.Unlock()
+	return sm
+}
+
+// Observe adds v to the observed values for sm.
+func (sm *Summary) Observe(v float64) {
+	sm.mu.Lock()
+	sm.rotateLocked()
+	sm.buckets[sm.curBucket].Insert(v)
+	sm.sum += v
+	sm.count++
+	sm.mu.Unlock()
+}
+
+// rotateLocked drops expired buckets. sm.mu must be held.
+func (sm *Summary) rotateLocked() {
+	now := time.Now()
+	for now.Sub(sm.lastRotate) >= sm.bucketAge {
+		sm.curBucket = (sm.curBucket + 1) % sm.ageBuckets
+		sm.buckets[sm.curBucket].Reset()
+		sm.lastRotate = sm.lastRotate.Add(sm.bucketAge)
+	}
+}
+
+// marshalTo marshals sm with the given prefix to w.
+func (sm *Summary) marshalTo(prefix string, w io.Writer) {
+	sm.mu.Lock()
+	sm.rotateLocked()
+	combined := newQuantileStream(sm.targets)
+	for _, b := range sm.buckets {
+		combined.Merge(b.Samples())
+	}
+	sum := sm.sum
+	count := sm.count
+	sm.mu.Unlock()
+
+	for _, q := range sm.quantiles {
+		v := combined.Query(q)
+		qs := strconv.FormatFloat(q, 'g', -1, 64)
+		fmt.Fprintf(w, "%s %g\n", addTag(prefix, "quantile", qs), v)
+	}
+	fmt.Fprintf(w, "%s %g\n", withSuffix(prefix, "_sum"), sum)
+	fmt.Fprintf(w, "%s %d\n", withSuffix(prefix, "_count"), count)
+}
+
+func (sm *Summary) metricType() string {
+	return "summary"
+}
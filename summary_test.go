@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+// quantile reads back sm's current combined quantile estimate for q, using
+// the same aggregation marshalTo performs, without parsing exposition text.
+func (sm *Summary) quantile(q float64) float64 {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.rotateLocked()
+	combined := newQuantileStream(sm.targets)
+	for _, b := range sm.buckets {
+		combined.Merge(b.Samples())
+	}
+	return combined.Query(q)
+}
+
+func TestSummaryQuantiles(t *testing.T) {
+	sm := newSummary(map[float64]float64{0.5: 0.01, 0.9: 0.01, 0.99: 0.001})
+
+	r := rand.New(rand.NewSource(1))
+	vals := make([]float64, 10000)
+	for i := range vals {
+		v := r.NormFloat64()*10 + 100
+		vals[i] = v
+		sm.Observe(v)
+	}
+	sort.Float64s(vals)
+
+	for _, tc := range []struct {
+		q       float64
+		maxDiff float64
+	}{
+		{0.5, 2},
+		{0.9, 2},
+		{0.99, 3},
+	} {
+		want := vals[int(tc.q*float64(len(vals)-1))]
+		got := sm.quantile(tc.q)
+		if diff := want - got; diff > tc.maxDiff || diff < -tc.maxDiff {
+			t.Errorf("q=%v: want ~%v, got %v (diff %v)", tc.q, want, got, diff)
+		}
+	}
+}
+
+func TestSummarySetWindowInvalidAgeBuckets(t *testing.T) {
+	sm := newSummary(map[float64]float64{0.5: 0.01})
+	defer func() {
+		if recover() == nil {
+			t.Fatal("SetWindow with ageBuckets=0 should panic")
+		}
+	}()
+	sm.SetWindow(time.Minute, 0)
+}
+
+func TestSummarySetWindowInvalidMaxAge(t *testing.T) {
+	sm := newSummary(map[float64]float64{0.5: 0.01})
+	defer func() {
+		if recover() == nil {
+			t.Fatal("SetWindow with maxAge<=0 should panic")
+		}
+	}()
+	sm.SetWindow(0, 5)
+}
+
+func TestSummaryCountAndSum(t *testing.T) {
+	sm := newSummary(map[float64]float64{0.5: 0.01})
+	for i := 1; i <= 5; i++ {
+		sm.Observe(float64(i))
+	}
+	if sm.count != 5 {
+		t.Errorf("count = %d, want 5", sm.count)
+	}
+	if sm.sum != 15 {
+		t.Errorf("sum = %v, want 15", sm.sum)
+	}
+}